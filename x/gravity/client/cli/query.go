@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	"github.com/mineplexio/mineplex-2-node/x/gravity/types"
+)
+
+// GetQueryCmd bundles all the query subcmds together so they appear under `gravity query`
+func GetQueryCmd() *cobra.Command {
+	// nolint: exhaustruct
+	gravityQueryCmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Gravity query subcommands",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	gravityQueryCmd.AddCommand([]*cobra.Command{
+		CmdQueryPendingAirdrops(),
+		CmdQueryIBCMetadataProposal(),
+	}...)
+
+	return gravityQueryCmd
+}
+
+// CmdQueryPendingAirdrops lists every airdrop proposal still draining out of the
+// PendingAirdropQueue, along with how many recipients remain unpaid
+func CmdQueryPendingAirdrops() *cobra.Command {
+	// nolint: exhaustruct
+	cmd := &cobra.Command{
+		Use:   "pending-airdrops",
+		Short: "Query in-flight queued airdrops",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			queryClient := types.NewQueryClient(cliCtx)
+
+			// nolint: exhaustruct
+			res, err := queryClient.PendingAirdrops(context.Background(), &types.QueryPendingAirdropsRequest{})
+			if err != nil {
+				return err
+			}
+
+			return cliCtx.PrintProto(res)
+		},
+	}
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryIBCMetadataProposal looks up the bank denom metadata governance has set for a bridged
+// ERC20 via an IBCMetadataProposal, if any
+func CmdQueryIBCMetadataProposal() *cobra.Command {
+	// nolint: exhaustruct
+	cmd := &cobra.Command{
+		Use:   "ibc-metadata [chain-id] [token-contract-address]",
+		Short: "Query the IBC denom metadata governance has set for a bridged token",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			queryClient := types.NewQueryClient(cliCtx)
+
+			// nolint: exhaustruct
+			res, err := queryClient.IBCMetadataProposal(context.Background(), &types.QueryIBCMetadataProposalRequest{
+				ChainId:       args[0],
+				TokenContract: args[1],
+			})
+			if err != nil {
+				return err
+			}
+
+			return cliCtx.PrintProto(res)
+		},
+	}
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
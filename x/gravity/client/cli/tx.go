@@ -1,11 +1,13 @@
 package cli
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/spf13/cobra"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -13,6 +15,7 @@ import (
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
 
 	"github.com/mineplexio/mineplex-2-node/x/gravity/keeper"
@@ -41,6 +44,11 @@ func GetTxCmd(storeKey string) *cobra.Command {
 		CmdSetOrchestratorAddress(),
 		CmdGovAirdropProposal(),
 		CmdGovUnhaltBridgeProposal(),
+		CmdGovIBCMetadataProposal(),
+		CmdGovMerkleAirdropProposal(),
+		CmdClaimAirdrop(),
+		CmdGovSetBridgeParamsProposal(),
+		CmdGovCancelAirdropProposal(),
 	}...)
 
 	return gravityTxCmd
@@ -319,6 +327,361 @@ func CmdRequestBatch() *cobra.Command {
 	return cmd
 }
 
+// IBCMetadataProposalPlain mirrors types.IBCMetadataProposal but keeps the bank Metadata as plain
+// JSON so that the proposal.json file is readable without hand encoding any binary fields
+type IBCMetadataProposalPlain struct {
+	Title         string
+	Description   string
+	ChainId       string
+	TokenContract string
+	Metadata      banktypes.Metadata
+}
+
+// CmdGovIBCMetadataProposal enables users to submit json file proposals that set the bank denom
+// metadata for a bridged ERC20, so that IBC clients of this chain can display the asset sanely
+func CmdGovIBCMetadataProposal() *cobra.Command {
+	// nolint: exhaustruct
+	cmd := &cobra.Command{
+		Use:   "gov-ibc-metadata [path-to-proposal-json] [initial-deposit]",
+		Short: "Creates a governance proposal to set the IBC denom metadata for a bridged token",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			cosmosAddr := cliCtx.GetFromAddress()
+
+			initialDeposit, err := sdk.ParseCoinsNormalized(args[1])
+			if err != nil {
+				return sdkerrors.Wrap(err, "bad initial deposit amount")
+			}
+
+			if len(initialDeposit) != 1 {
+				return fmt.Errorf("unexpected coin amounts, expecting just 1 coin amount for initialDeposit")
+			}
+
+			proposalFile := args[0]
+
+			contents, err := os.ReadFile(proposalFile)
+			if err != nil {
+				return sdkerrors.Wrap(err, "failed to read proposal json file")
+			}
+
+			proposal := &IBCMetadataProposalPlain{}
+			err = json.Unmarshal(contents, proposal)
+			if err != nil {
+				return sdkerrors.Wrap(err, "proposal json file is not valid json")
+			}
+
+			finalProposal := &types.IBCMetadataProposal{
+				Title:         proposal.Title,
+				Description:   proposal.Description,
+				ChainId:       proposal.ChainId,
+				TokenContract: proposal.TokenContract,
+				Metadata:      proposal.Metadata,
+			}
+
+			proposalAny, err := codectypes.NewAnyWithValue(finalProposal)
+			if err != nil {
+				return sdkerrors.Wrap(err, "invalid metadata or proposal details!")
+			}
+
+			// Make the message
+			msg := govtypes.MsgSubmitProposal{
+				Proposer:       cosmosAddr.String(),
+				InitialDeposit: initialDeposit,
+				Content:        proposalAny,
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// Send it
+			return tx.GenerateOrBroadcastTxCLI(cliCtx, cmd.Flags(), &msg)
+		},
+	}
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// MerkleAirdropProposalPlain mirrors types.MerkleAirdropProposal but keeps the Merkle root as a
+// hex string so that the proposal.json file stays human readable
+type MerkleAirdropProposalPlain struct {
+	Title        string
+	Description  string
+	Denom        string
+	TotalAmount  uint64
+	MerkleRoot   string
+	ClaimWindow  int64
+	ExpiryHeight uint64
+}
+
+// CmdGovMerkleAirdropProposal enables users to submit json file proposals that stand up a
+// claim-based airdrop campaign, funded from the community pool, instead of pushing transfers to
+// every recipient inside the proposal handler
+func CmdGovMerkleAirdropProposal() *cobra.Command {
+	// nolint: exhaustruct
+	cmd := &cobra.Command{
+		Use:   "gov-merkle-airdrop [path-to-proposal-json] [initial-deposit]",
+		Short: "Creates a governance proposal for a merkle-claim airdrop campaign",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			cosmosAddr := cliCtx.GetFromAddress()
+
+			initialDeposit, err := sdk.ParseCoinsNormalized(args[1])
+			if err != nil {
+				return sdkerrors.Wrap(err, "bad initial deposit amount")
+			}
+
+			if len(initialDeposit) != 1 {
+				return fmt.Errorf("unexpected coin amounts, expecting just 1 coin amount for initialDeposit")
+			}
+
+			proposalFile := args[0]
+
+			contents, err := os.ReadFile(proposalFile)
+			if err != nil {
+				return sdkerrors.Wrap(err, "failed to read proposal json file")
+			}
+
+			proposal := &MerkleAirdropProposalPlain{}
+			err = json.Unmarshal(contents, proposal)
+			if err != nil {
+				return sdkerrors.Wrap(err, "proposal json file is not valid json")
+			}
+
+			merkleRoot, err := hex.DecodeString(proposal.MerkleRoot)
+			if err != nil {
+				return sdkerrors.Wrap(err, "merkle root is not valid hex")
+			}
+
+			finalProposal := &types.MerkleAirdropProposal{
+				Title:        proposal.Title,
+				Description:  proposal.Description,
+				Denom:        proposal.Denom,
+				TotalAmount:  proposal.TotalAmount,
+				MerkleRoot:   merkleRoot,
+				ClaimWindow:  proposal.ClaimWindow,
+				ExpiryHeight: proposal.ExpiryHeight,
+			}
+
+			proposalAny, err := codectypes.NewAnyWithValue(finalProposal)
+			if err != nil {
+				return sdkerrors.Wrap(err, "invalid metadata or proposal details!")
+			}
+
+			// Make the message
+			msg := govtypes.MsgSubmitProposal{
+				Proposer:       cosmosAddr.String(),
+				InitialDeposit: initialDeposit,
+				Content:        proposalAny,
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// Send it
+			return tx.GenerateOrBroadcastTxCLI(cliCtx, cmd.Flags(), &msg)
+		},
+	}
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdClaimAirdrop lets a recipient of a merkle airdrop campaign redeem their allotment by
+// presenting the Merkle proof the campaign operator handed out off-chain
+func CmdClaimAirdrop() *cobra.Command {
+	// nolint: exhaustruct
+	cmd := &cobra.Command{
+		Use:   "claim-airdrop [campaign-id] [index] [amount] [proof-hex-csv]",
+		Short: "Claims a recipient's share of a merkle airdrop campaign",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			cosmosAddr := cliCtx.GetFromAddress()
+
+			campaignID, err := strconv.ParseUint(args[0], 0, 64)
+			if err != nil {
+				return sdkerrors.Wrap(err, "failed to parse campaign id")
+			}
+			index, err := strconv.ParseUint(args[1], 0, 64)
+			if err != nil {
+				return sdkerrors.Wrap(err, "failed to parse index")
+			}
+			amount, err := strconv.ParseUint(args[2], 0, 64)
+			if err != nil {
+				return sdkerrors.Wrap(err, "failed to parse amount")
+			}
+
+			var proof [][]byte
+			if args[3] != "" {
+				for _, hexNode := range strings.Split(args[3], ",") {
+					node, err := hex.DecodeString(hexNode)
+					if err != nil {
+						return sdkerrors.Wrap(err, "proof entry is not valid hex")
+					}
+					proof = append(proof, node)
+				}
+			}
+
+			// Make the message
+			msg := types.MsgClaimAirdrop{
+				Claimer:    cosmosAddr.String(),
+				CampaignId: campaignID,
+				Index:      index,
+				Amount:     amount,
+				Proof:      proof,
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// Send it
+			return tx.GenerateOrBroadcastTxCLI(cliCtx, cmd.Flags(), &msg)
+		},
+	}
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// SetBridgeParamsProposalPlain mirrors types.SetBridgeParamsProposal so that the proposal.json
+// file stays readable
+type SetBridgeParamsProposalPlain struct {
+	Title       string
+	Description string
+	ChainId     string
+	Updates     map[string]string
+}
+
+// CmdGovSetBridgeParamsProposal enables users to submit json file proposals that tune a chain's
+// bridge params without going through the generic x/params proposal
+func CmdGovSetBridgeParamsProposal() *cobra.Command {
+	// nolint: exhaustruct
+	cmd := &cobra.Command{
+		Use:   "gov-set-bridge-params [path-to-proposal-json] [initial-deposit]",
+		Short: "Creates a governance proposal to update a chain's bridge params",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			cosmosAddr := cliCtx.GetFromAddress()
+
+			initialDeposit, err := sdk.ParseCoinsNormalized(args[1])
+			if err != nil {
+				return sdkerrors.Wrap(err, "bad initial deposit amount")
+			}
+
+			if len(initialDeposit) != 1 {
+				return fmt.Errorf("unexpected coin amounts, expecting just 1 coin amount for initialDeposit")
+			}
+
+			proposalFile := args[0]
+
+			contents, err := os.ReadFile(proposalFile)
+			if err != nil {
+				return sdkerrors.Wrap(err, "failed to read proposal json file")
+			}
+
+			proposal := &SetBridgeParamsProposalPlain{}
+			err = json.Unmarshal(contents, proposal)
+			if err != nil {
+				return sdkerrors.Wrap(err, "proposal json file is not valid json")
+			}
+
+			finalProposal := &types.SetBridgeParamsProposal{
+				Title:       proposal.Title,
+				Description: proposal.Description,
+				ChainId:     proposal.ChainId,
+				Updates:     proposal.Updates,
+			}
+
+			proposalAny, err := codectypes.NewAnyWithValue(finalProposal)
+			if err != nil {
+				return sdkerrors.Wrap(err, "invalid metadata or proposal details!")
+			}
+
+			// Make the message
+			msg := govtypes.MsgSubmitProposal{
+				Proposer:       cosmosAddr.String(),
+				InitialDeposit: initialDeposit,
+				Content:        proposalAny,
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// Send it
+			return tx.GenerateOrBroadcastTxCLI(cliCtx, cmd.Flags(), &msg)
+		},
+	}
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdGovCancelAirdropProposal enables users to submit proposals that cancel an in-flight queued
+// airdrop, identified by its queue id, refunding whatever hasn't been paid out yet to the
+// community pool. The title and description are generated from the queue id; no json file is read
+func CmdGovCancelAirdropProposal() *cobra.Command {
+	// nolint: exhaustruct
+	cmd := &cobra.Command{
+		Use:   "gov-cancel-airdrop [queue-id] [initial-deposit]",
+		Short: "Creates a governance proposal to cancel a queued airdrop and refund the remainder",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			cosmosAddr := cliCtx.GetFromAddress()
+
+			queueID, err := strconv.ParseUint(args[0], 0, 64)
+			if err != nil {
+				return sdkerrors.Wrap(err, "failed to parse queue id")
+			}
+
+			initialDeposit, err := sdk.ParseCoinsNormalized(args[1])
+			if err != nil {
+				return sdkerrors.Wrap(err, "bad initial deposit amount")
+			}
+
+			if len(initialDeposit) != 1 {
+				return fmt.Errorf("unexpected coin amounts, expecting just 1 coin amount for initialDeposit")
+			}
+
+			proposal := &types.CancelAirdropProposal{
+				Title:       fmt.Sprintf("Cancel airdrop queue %d", queueID),
+				Description: fmt.Sprintf("Cancels pending airdrop queue %d and refunds the unpaid remainder to the community pool", queueID),
+				QueueId:     queueID,
+			}
+
+			proposalAny, err := codectypes.NewAnyWithValue(proposal)
+			if err != nil {
+				return sdkerrors.Wrap(err, "invalid metadata or proposal details!")
+			}
+
+			// Make the message
+			msg := govtypes.MsgSubmitProposal{
+				Proposer:       cosmosAddr.String(),
+				InitialDeposit: initialDeposit,
+				Content:        proposalAny,
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// Send it
+			return tx.GenerateOrBroadcastTxCLI(cliCtx, cmd.Flags(), &msg)
+		},
+	}
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
 // CmdSetOrchestratorAddress registers delegate keys for a validator so that their Orchestrator has authority to perform
 // its responsibility
 func CmdSetOrchestratorAddress() *cobra.Command {
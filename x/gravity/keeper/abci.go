@@ -0,0 +1,12 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// EndBlocker is called at the end of every block and drives periodic module housekeeping that
+// can't happen synchronously inside a single message or governance proposal handler
+func (k Keeper) EndBlocker(ctx sdk.Context) {
+	k.sweepExpiredAirdropCampaigns(ctx)
+	k.drainPendingAirdrops(ctx)
+}
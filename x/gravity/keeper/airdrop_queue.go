@@ -0,0 +1,231 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	disttypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+
+	"github.com/mineplexio/mineplex-2-node/x/gravity/types"
+)
+
+// this file contains the PendingAirdropQueue mechanics HandleAirdropProposal falls back to once a
+// proposal's recipient list is too large to pay out synchronously in one block. Queued recipients
+// are drained a few at a time by the EndBlocker instead of all at once.
+//
+// The sync/queue threshold and the per-block drain cap below are hardcoded constants. No Params
+// field backs either one, so governance cannot change them; changing them takes a binary upgrade.
+
+const (
+	// DefaultMaxSyncAirdropRecipients is the largest recipient count for which
+	// HandleAirdropProposal still pays out every recipient synchronously, matching its historical
+	// behavior for small drops; a drop with more recipients is queued
+	DefaultMaxSyncAirdropRecipients = 200
+
+	// DefaultMaxAirdropTransfersPerBlock bounds how many queued recipients the EndBlocker drains
+	// out of any single PendingAirdropQueue per block
+	DefaultMaxAirdropTransfersPerBlock = 500
+)
+
+// GetMaxSyncAirdropRecipients returns the largest recipient count for which an airdrop proposal
+// is paid out synchronously instead of being queued. It always returns
+// DefaultMaxSyncAirdropRecipients: no Params field backs it, so governance cannot tune it.
+func (k Keeper) GetMaxSyncAirdropRecipients(ctx sdk.Context) int {
+	return DefaultMaxSyncAirdropRecipients
+}
+
+// GetMaxAirdropTransfersPerBlock returns the cap on how many queued recipients the EndBlocker
+// pays out of a single PendingAirdropQueue per block. It always returns
+// DefaultMaxAirdropTransfersPerBlock; like GetMaxSyncAirdropRecipients, no Params field backs it.
+func (k Keeper) GetMaxAirdropTransfersPerBlock(ctx sdk.Context) int {
+	return DefaultMaxAirdropTransfersPerBlock
+}
+
+// airdropQueueEscrowAddress derives the address that holds one queue's reserved funds between
+// funding and drain/refund/cancel. This is deliberately a plain derived account, not a module
+// account registered with AccountKeeper - see airdropCampaignEscrowAddress in merkle_airdrop.go
+// for why a fresh name built from a runtime-assigned queue id can never be pre-registered there
+func airdropQueueEscrowAddress(queueID uint64) sdk.AccAddress {
+	return authtypes.NewModuleAddress(fmt.Sprintf("gravity-airdrop-queue-%d", queueID))
+}
+
+// GetNextAirdropQueueID returns a fresh, never reused id for a PendingAirdropQueue and advances
+// the counter
+func (k Keeper) GetNextAirdropQueueID(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	var id uint64
+	if bz := store.Get(types.PendingAirdropQueueNonceKey); bz != nil {
+		id = sdk.BigEndianToUint64(bz)
+	}
+	id++
+	store.Set(types.PendingAirdropQueueNonceKey, sdk.Uint64ToBigEndian(id))
+	return id
+}
+
+// SetPendingAirdropQueue stores or updates a queued airdrop
+func (k Keeper) SetPendingAirdropQueue(ctx sdk.Context, queue types.PendingAirdropQueue) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetPendingAirdropQueueKey(queue.QueueId), k.cdc.MustMarshal(&queue))
+}
+
+// GetPendingAirdropQueue looks up a queued airdrop by the queue id GetNextAirdropQueueID assigned it
+func (k Keeper) GetPendingAirdropQueue(ctx sdk.Context, queueID uint64) (types.PendingAirdropQueue, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetPendingAirdropQueueKey(queueID))
+	if bz == nil {
+		return types.PendingAirdropQueue{}, false
+	}
+	var queue types.PendingAirdropQueue
+	k.cdc.MustUnmarshal(bz, &queue)
+	return queue, true
+}
+
+// DeletePendingAirdropQueue removes a queue once it has fully drained or been cancelled
+func (k Keeper) DeletePendingAirdropQueue(ctx sdk.Context, queueID uint64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetPendingAirdropQueueKey(queueID))
+}
+
+// IteratePendingAirdropQueues iterates over every in-flight queued airdrop, used by the
+// EndBlocker drain and by QueryPendingAirdrops
+func (k Keeper) IteratePendingAirdropQueues(ctx sdk.Context, cb func(queue types.PendingAirdropQueue) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.PendingAirdropQueueKey)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var queue types.PendingAirdropQueue
+		k.cdc.MustUnmarshal(iter.Value(), &queue)
+		if cb(queue) {
+			break
+		}
+	}
+}
+
+// QueryPendingAirdrops returns every currently queued airdrop, surfaced over gRPC/CLI by the
+// PendingAirdrops query
+func (k Keeper) QueryPendingAirdrops(ctx sdk.Context) []types.PendingAirdropQueue {
+	var queues []types.PendingAirdropQueue
+	k.IteratePendingAirdropQueues(ctx, func(queue types.PendingAirdropQueue) bool {
+		queues = append(queues, queue)
+		return false
+	})
+	return queues
+}
+
+// queueAirdrop stores the parsed recipients for the EndBlocker to drain and emits
+// EventAirdropStarted, leaving the funds already reserved by the caller in the queue's escrow
+// account
+func (k Keeper) queueAirdrop(ctx sdk.Context, queueID uint64, denom string, recipients []sdk.AccAddress, amounts []uint64) error {
+	entries := make([]types.PendingAirdropEntry, len(recipients))
+	for i, addr := range recipients {
+		entries[i] = types.PendingAirdropEntry{
+			Recipient: addr.String(),
+			Amount:    amounts[i],
+		}
+	}
+
+	queue := types.PendingAirdropQueue{
+		QueueId: queueID,
+		Denom:   denom,
+		Entries: entries,
+		Cursor:  0,
+	}
+	k.SetPendingAirdropQueue(ctx, queue)
+
+	return ctx.EventManager().EmitTypedEvent(&types.EventAirdropStarted{
+		QueueId:   queueID,
+		Denom:     denom,
+		Remaining: uint64(len(entries)),
+	})
+}
+
+// drainPendingAirdrops pays out up to GetMaxAirdropTransfersPerBlock entries from every in-flight
+// queue, called once per block from the EndBlocker
+func (k Keeper) drainPendingAirdrops(ctx sdk.Context) {
+	maxPerQueue := k.GetMaxAirdropTransfersPerBlock(ctx)
+
+	var queues []types.PendingAirdropQueue
+	k.IteratePendingAirdropQueues(ctx, func(queue types.PendingAirdropQueue) bool {
+		queues = append(queues, queue)
+		return false
+	})
+
+	for _, queue := range queues {
+		escrowAddr := airdropQueueEscrowAddress(queue.QueueId)
+		end := queue.Cursor + uint64(maxPerQueue)
+		if end > uint64(len(queue.Entries)) {
+			end = uint64(len(queue.Entries))
+		}
+
+		for ; queue.Cursor < end; queue.Cursor++ {
+			entry := queue.Entries[queue.Cursor]
+			coins := sdk.NewCoins(sdk.NewCoin(queue.Denom, sdk.NewIntFromUint64(entry.Amount)))
+
+			addr, err := sdk.AccAddressFromBech32(entry.Recipient)
+			paid := false
+			if err == nil {
+				paid = k.bankKeeper.SendCoins(ctx, escrowAddr, addr, coins) == nil
+			}
+			if !paid {
+				// the entry can never be paid out (bad address) or the send itself failed; rather
+				// than advance past it and strand its reserved coins in the queue's now-orphaned
+				// escrow account, refund them to the community pool immediately so the
+				// startingSupply == endingSupply invariant still holds
+				ctx.Logger().Error("failed to pay pending airdrop entry, refunding to community pool", "queueId", queue.QueueId, "recipient", entry.Recipient)
+				if refundErr := k.bankKeeper.SendCoinsFromAccountToModule(ctx, escrowAddr, disttypes.ModuleName, coins); refundErr != nil {
+					// leave the cursor where it is so this entry is retried next block rather
+					// than silently advancing past coins we failed to move anywhere
+					ctx.Logger().Error("failed to refund unpayable airdrop entry, will retry next block", "queueId", queue.QueueId, "recipient", entry.Recipient, "error", refundErr)
+					break
+				}
+				feePool := k.DistKeeper.GetFeePool(ctx)
+				feePool.CommunityPool = feePool.CommunityPool.Add(sdk.NewDecCoinsFromCoins(coins...)...)
+				k.DistKeeper.SetFeePool(ctx, feePool)
+			}
+		}
+
+		remaining := uint64(len(queue.Entries)) - queue.Cursor
+		if remaining == 0 {
+			if err := ctx.EventManager().EmitTypedEvent(&types.EventAirdropCompleted{QueueId: queue.QueueId}); err != nil {
+				ctx.Logger().Error("failed to emit EventAirdropCompleted", "queueId", queue.QueueId, "error", err)
+			}
+			k.DeletePendingAirdropQueue(ctx, queue.QueueId)
+			continue
+		}
+
+		k.SetPendingAirdropQueue(ctx, queue)
+		if err := ctx.EventManager().EmitTypedEvent(&types.EventAirdropProgress{QueueId: queue.QueueId, Remaining: remaining}); err != nil {
+			ctx.Logger().Error("failed to emit EventAirdropProgress", "queueId", queue.QueueId, "error", err)
+		}
+	}
+}
+
+// cancelQueuedAirdrop refunds whatever is left unpaid in a queue back to the community pool and
+// removes it, used by CancelAirdropProposal
+func (k Keeper) cancelQueuedAirdrop(ctx sdk.Context, queueID uint64) error {
+	queue, found := k.GetPendingAirdropQueue(ctx, queueID)
+	if !found {
+		return sdkerrors.Wrap(types.ErrUnknown, "no such pending airdrop")
+	}
+
+	remainderTotal := sdk.NewInt(0)
+	for _, entry := range queue.Entries[queue.Cursor:] {
+		remainderTotal = remainderTotal.Add(sdk.NewIntFromUint64(entry.Amount))
+	}
+
+	if remainderTotal.IsPositive() {
+		escrowAddr := airdropQueueEscrowAddress(queueID)
+		remainderCoins := sdk.NewCoins(sdk.NewCoin(queue.Denom, remainderTotal))
+		if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, escrowAddr, disttypes.ModuleName, remainderCoins); err != nil {
+			return sdkerrors.Wrap(err, "failed to refund cancelled airdrop")
+		}
+		feePool := k.DistKeeper.GetFeePool(ctx)
+		feePool.CommunityPool = feePool.CommunityPool.Add(sdk.NewDecCoinsFromCoins(remainderCoins...)...)
+		k.DistKeeper.SetFeePool(ctx, feePool)
+	}
+
+	k.DeletePendingAirdropQueue(ctx, queueID)
+	return nil
+}
@@ -2,10 +2,12 @@ package keeper
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	disttypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
 	"github.com/mineplexio/mineplex-2-node/x/gravity/types"
@@ -39,6 +41,24 @@ func RegisterProposalTypes() {
 		// nolint: exhaustruct
 		//govtypes.RegisterProposalTypeCodec(&types.AirdropProposal{}, airdrop)
 	}
+	merkleAirdrop := "gravity/MerkleAirdrop"
+	if !govtypes.IsValidProposalType(strings.TrimPrefix(merkleAirdrop, prefix)) {
+		govtypes.RegisterProposalType(types.ProposalTypeMerkleAirdrop)
+		// nolint: exhaustruct
+		//govtypes.RegisterProposalTypeCodec(&types.MerkleAirdropProposal{}, merkleAirdrop)
+	}
+	setBridgeParams := "gravity/SetBridgeParams"
+	if !govtypes.IsValidProposalType(strings.TrimPrefix(setBridgeParams, prefix)) {
+		govtypes.RegisterProposalType(types.ProposalTypeSetBridgeParams)
+		// nolint: exhaustruct
+		//govtypes.RegisterProposalTypeCodec(&types.SetBridgeParamsProposal{}, setBridgeParams)
+	}
+	cancelAirdrop := "gravity/CancelAirdrop"
+	if !govtypes.IsValidProposalType(strings.TrimPrefix(cancelAirdrop, prefix)) {
+		govtypes.RegisterProposalType(types.ProposalTypeCancelAirdrop)
+		// nolint: exhaustruct
+		//govtypes.RegisterProposalTypeCodec(&types.CancelAirdropProposal{}, cancelAirdrop)
+	}
 }
 
 func NewGravityProposalHandler(k Keeper) govtypes.Handler {
@@ -48,6 +68,14 @@ func NewGravityProposalHandler(k Keeper) govtypes.Handler {
 			return k.HandleUnhaltBridgeProposal(ctx, c)
 		case *types.AirdropProposal:
 			return k.HandleAirdropProposal(ctx, c)
+		case *types.IBCMetadataProposal:
+			return k.HandleIBCMetadataProposal(ctx, c)
+		case *types.MerkleAirdropProposal:
+			return k.HandleMerkleAirdropProposal(ctx, c)
+		case *types.SetBridgeParamsProposal:
+			return k.HandleSetBridgeParamsProposal(ctx, c)
+		case *types.CancelAirdropProposal:
+			return k.HandleCancelAirdropProposal(ctx, c)
 
 		default:
 			return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized Gravity proposal content type: %T", c)
@@ -118,7 +146,10 @@ func pruneAttestationsAfterNonce(ctx sdk.Context, chainID types.ChainID, k Keepe
 	}
 }
 
-// Allows governance to deploy an airdrop to a provided list of addresses
+// Allows governance to deploy an airdrop to a provided list of addresses. Drops small enough to
+// fit comfortably in one block are still executed synchronously here; larger drops are handed off
+// to the PendingAirdropQueue and drained a few transfers at a time by the EndBlocker instead, so
+// that a proposal which fits under the tx size limit can't still blow the block gas limit.
 func (k Keeper) HandleAirdropProposal(ctx sdk.Context, p *types.AirdropProposal) error {
 	ctx.Logger().Info("Gov vote passed: Performing airdrop")
 	startingSupply := k.bankKeeper.GetSupply(ctx, p.Denom)
@@ -169,6 +200,26 @@ func (k Keeper) HandleAirdropProposal(ctx sdk.Context, p *types.AirdropProposal)
 		return sdkerrors.Wrap(types.ErrInvalid, "Invalid recipients")
 	}
 
+	// reserve the funds up front by moving them out of the community pool, this happens whether
+	// we pay out synchronously below or queue the recipients for the EndBlocker to drain, so the
+	// startingSupply == endingSupply invariant holds either way
+	newCoins, invalidModuleBalance := feePool.CommunityPool.SafeSub(sdk.NewDecCoins(totalRequiredDecCoin))
+	// this shouldn't ever happen because we check that we have enough before starting
+	// but lets be conservative.
+	if invalidModuleBalance {
+		return sdkerrors.Wrap(types.ErrInvalid, "internal error!")
+	}
+	feePool.CommunityPool = newCoins
+	k.DistKeeper.SetFeePool(ctx, feePool)
+
+	if numRecipients > k.GetMaxSyncAirdropRecipients(ctx) {
+		queueID := k.GetNextAirdropQueueID(ctx)
+		if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, disttypes.ModuleName, airdropQueueEscrowAddress(queueID), sdk.NewCoins(sdk.NewCoin(p.Denom, airdropTotal))); err != nil {
+			return sdkerrors.Wrap(err, "failed to reserve airdrop funds")
+		}
+		return k.queueAirdrop(ctx, queueID, p.Denom, parsedRecipients, p.Amounts)
+	}
+
 	// the total amount actually sent in dec coins
 	totalSent := sdk.NewDec(0)
 	for i, addr := range parsedRecipients {
@@ -192,19 +243,222 @@ func (k Keeper) HandleAirdropProposal(ctx sdk.Context, p *types.AirdropProposal)
 		return sdkerrors.Wrap(types.ErrInvalid, "Invalid amount sent")
 	}
 
-	newCoins, InvalidModuleBalance := feePool.CommunityPool.SafeSub(sdk.NewDecCoins(totalRequiredDecCoin))
-	// this shouldn't ever happen because we check that we have enough before starting
-	// but lets be conservative.
-	if InvalidModuleBalance {
+	endingSupply := k.bankKeeper.GetSupply(ctx, p.Denom)
+	if !startingSupply.Equal(endingSupply) {
+		return sdkerrors.Wrap(types.ErrInvalid, "total chain supply has changed!")
+	}
+
+	return nil
+}
+
+// Allows governance to cancel an in-flight PendingAirdropQueue, refunding whatever hasn't been
+// paid out yet back to the community pool
+func (k Keeper) HandleCancelAirdropProposal(ctx sdk.Context, p *types.CancelAirdropProposal) error {
+	ctx.Logger().Info("Gov vote passed: Cancelling pending airdrop", "queueId", p.QueueId)
+	return k.cancelQueuedAirdrop(ctx, p.QueueId)
+}
+
+// Allows governance to set the bank denom metadata for a bridged ERC20, so that IBC clients
+// and other chains observing this one over IBC can display bridged assets sanely
+func (k Keeper) HandleIBCMetadataProposal(ctx sdk.Context, p *types.IBCMetadataProposal) error {
+	ctx.Logger().Info("Gov vote passed: Setting IBC metadata", "denom", p.Metadata.Base)
+
+	if err := p.Metadata.Validate(); err != nil {
+		ctx.Logger().Info("IBC metadata proposal failed to execute invalid metadata!")
+		return sdkerrors.Wrap(types.ErrInvalid, "invalid denom metadata")
+	}
+	if strings.TrimSpace(p.Metadata.Name) == "" {
+		ctx.Logger().Info("IBC metadata proposal failed to execute missing name!")
+		return sdkerrors.Wrap(types.ErrInvalid, "metadata name must not be empty")
+	}
+
+	chainID := types.ChainID(p.ChainId)
+	tokenContract, err := types.NewEthAddress(p.TokenContract)
+	if err != nil {
+		ctx.Logger().Info("IBC metadata proposal failed to execute invalid token contract!")
+		return sdkerrors.Wrap(types.ErrInvalid, "invalid token contract address")
+	}
+
+	erc20Data, found := k.GetERC20Data(ctx, chainID, *tokenContract)
+	if !found {
+		return sdkerrors.Wrap(types.ErrUnknown, "no ERC20 data on record for this token contract, it must be bridged at least once before metadata can be set")
+	}
+
+	// cross check the proposed metadata against what we already know about the bridged ERC20
+	// so that we can't end up with denom metadata that lies about what's actually bridged. Base
+	// must match the canonical gravity denom for this chain/contract specifically - SetDenomMetaData
+	// stores purely keyed on Base, so without this check a proposal could set Base to any other
+	// existing denom (e.g. the staking token) and silently overwrite its metadata, and GetIBCMetadataProposal
+	// (which looks up by GravityDenom) would never find what was actually stored
+	if p.Metadata.Base != types.GravityDenom(chainID, *tokenContract) {
+		return sdkerrors.Wrap(types.ErrInvalid, "metadata base does not match the bridged token's gravity denom")
+	}
+	if erc20Data.Name != p.Metadata.Name {
+		return sdkerrors.Wrap(types.ErrInvalid, "metadata name does not match the bridged ERC20 name")
+	}
+	if erc20Data.Symbol != p.Metadata.Symbol {
+		return sdkerrors.Wrap(types.ErrInvalid, "metadata symbol does not match the bridged ERC20 symbol")
+	}
+	var displayExponent uint32
+	foundDisplayUnit := false
+	for _, unit := range p.Metadata.DenomUnits {
+		if unit.Denom == p.Metadata.Display {
+			displayExponent = unit.Exponent
+			foundDisplayUnit = true
+		}
+	}
+	if !foundDisplayUnit || displayExponent != uint32(erc20Data.Decimals) {
+		return sdkerrors.Wrap(types.ErrInvalid, "metadata decimals do not match the bridged ERC20 decimals")
+	}
+
+	k.bankKeeper.SetDenomMetaData(ctx, p.Metadata)
+
+	// if Gravity itself deployed this ERC20 on the counterparty chain, the remote side has no
+	// other way to learn the name/symbol/decimals we just set, so queue a deployERC20 call to
+	// be picked up by the next batch so it gets pushed over to Ethereum
+	if k.IsOnChainOriginated(ctx, chainID, *tokenContract) {
+		k.QueueDeployERC20(ctx, chainID, p.Metadata.Name, p.Metadata.Display, uint8(displayExponent))
+	}
+
+	return nil
+}
+
+// Allows governance to stand up a claim-based airdrop campaign instead of pushing transfers to
+// every recipient in the proposal handler. This keeps large drops from blowing past block gas or
+// the 20-byte-packed-recipients size limit used by HandleAirdropProposal
+func (k Keeper) HandleMerkleAirdropProposal(ctx sdk.Context, p *types.MerkleAirdropProposal) error {
+	ctx.Logger().Info("Gov vote passed: Creating merkle airdrop campaign", "denom", p.Denom)
+
+	if err := sdk.ValidateDenom(p.Denom); err != nil {
+		ctx.Logger().Info("Merkle airdrop failed to execute invalid denom!")
+		return sdkerrors.Wrap(types.ErrInvalid, "invalid airdrop denom")
+	}
+	if p.TotalAmount == 0 {
+		return sdkerrors.Wrap(types.ErrInvalid, "total amount must be positive")
+	}
+	if p.ExpiryHeight <= uint64(ctx.BlockHeight()) {
+		return sdkerrors.Wrap(types.ErrInvalid, "expiry height must be in the future")
+	}
+	if p.ClaimWindow < 0 {
+		return sdkerrors.Wrap(types.ErrInvalid, "claim window must not be negative")
+	}
+
+	feePool := k.DistKeeper.GetFeePool(ctx)
+	totalRequiredDecCoin := sdk.NewDecCoinFromCoin(sdk.NewCoin(p.Denom, sdk.NewIntFromUint64(p.TotalAmount)))
+	if totalRequiredDecCoin.Amount.GT(feePool.CommunityPool.AmountOf(p.Denom)) {
+		ctx.Logger().Info("Merkle airdrop failed to execute insufficient tokens in the community pool!")
+		return sdkerrors.Wrap(types.ErrInvalid, "insufficient tokens in community pool")
+	}
+
+	campaignID := k.GetNextAirdropCampaignID(ctx)
+	escrowAddr := airdropCampaignEscrowAddress(campaignID)
+
+	newCoins, invalidModuleBalance := feePool.CommunityPool.SafeSub(sdk.NewDecCoins(totalRequiredDecCoin))
+	if invalidModuleBalance {
 		return sdkerrors.Wrap(types.ErrInvalid, "internal error!")
 	}
 	feePool.CommunityPool = newCoins
 	k.DistKeeper.SetFeePool(ctx, feePool)
 
-	endingSupply := k.bankKeeper.GetSupply(ctx, p.Denom)
-	if !startingSupply.Equal(endingSupply) {
-		return sdkerrors.Wrap(types.ErrInvalid, "total chain supply has changed!")
+	amountCoins := sdk.NewCoins(sdk.NewCoin(p.Denom, sdk.NewIntFromUint64(p.TotalAmount)))
+	if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, disttypes.ModuleName, escrowAddr, amountCoins); err != nil {
+		return sdkerrors.Wrap(err, "failed to fund merkle airdrop campaign")
 	}
 
+	campaign := types.AirdropCampaign{
+		CampaignId:    campaignID,
+		Denom:         p.Denom,
+		TotalAmount:   p.TotalAmount,
+		ClaimedAmount: 0,
+		MerkleRoot:    p.MerkleRoot,
+		ClaimWindow:   p.ClaimWindow,
+		ExpiryHeight:  p.ExpiryHeight,
+		CreatedHeight: uint64(ctx.BlockHeight()),
+	}
+	k.SetAirdropCampaign(ctx, campaign)
+
 	return nil
 }
+
+// setBridgeParamsWhitelist enumerates the per-chain params SetBridgeParamsProposal is allowed to
+// touch, along with the range check each update must pass before it's merged in. This mirrors the
+// checks Params.ValidateBasic already applies to these same fields.
+var setBridgeParamsWhitelist = map[string]func(sdk.Context, *types.Params, string) error{
+	"SignedValsetsWindow": func(_ sdk.Context, p *types.Params, raw string) error {
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || v == 0 {
+			return sdkerrors.Wrap(types.ErrInvalid, "SignedValsetsWindow must be a positive integer")
+		}
+		p.SignedValsetsWindow = v
+		return nil
+	},
+	"SignedBatchesWindow": func(_ sdk.Context, p *types.Params, raw string) error {
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || v == 0 {
+			return sdkerrors.Wrap(types.ErrInvalid, "SignedBatchesWindow must be a positive integer")
+		}
+		p.SignedBatchesWindow = v
+		return nil
+	},
+	"SlashFractionBatch": func(_ sdk.Context, p *types.Params, raw string) error {
+		v, err := sdk.NewDecFromStr(raw)
+		if err != nil || v.IsNegative() || v.GT(sdk.OneDec()) {
+			return sdkerrors.Wrap(types.ErrInvalid, "SlashFractionBatch must be a decimal between 0 and 1")
+		}
+		p.SlashFractionBatch = v
+		return nil
+	},
+	"MinChainFeeBasisPoints": func(_ sdk.Context, p *types.Params, raw string) error {
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || v > 10000 {
+			return sdkerrors.Wrap(types.ErrInvalid, "MinChainFeeBasisPoints must be between 0 and 10000")
+		}
+		p.MinChainFeeBasisPoints = v
+		return nil
+	},
+	"BridgeEthereumBlockDelay": func(_ sdk.Context, p *types.Params, raw string) error {
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return sdkerrors.Wrap(types.ErrInvalid, "BridgeEthereumBlockDelay must be an integer")
+		}
+		p.BridgeEthereumBlockDelay = v
+		return nil
+	},
+}
+
+// Allows governance to tune per-chain bridge params without going through the generic x/params
+// proposal, which can't express the per-chain-id scoping used by types.ChainID
+//
+// Only the fields in setBridgeParamsWhitelist can be changed. This proposal has no pause switch:
+// nothing it does halts MsgSendToEth, batch creation or attestation processing.
+func (k Keeper) HandleSetBridgeParamsProposal(ctx sdk.Context, p *types.SetBridgeParamsProposal) error {
+	ctx.Logger().Info("Gov vote passed: Updating bridge params", "chainId", p.ChainId)
+
+	chainID := types.ChainID(p.ChainId)
+	params := k.GetParamsForChain(ctx, chainID)
+
+	for field, rawValue := range p.Updates {
+		apply, ok := setBridgeParamsWhitelist[field]
+		if !ok {
+			return sdkerrors.Wrapf(types.ErrInvalid, "%s is not an updatable bridge param", field)
+		}
+		if err := apply(ctx, &params, rawValue); err != nil {
+			return err
+		}
+	}
+
+	if err := params.ValidateBasic(); err != nil {
+		return sdkerrors.Wrap(types.ErrInvalid, "resulting params are invalid")
+	}
+	k.SetParamsForChain(ctx, chainID, params)
+
+	return nil
+}
+
+// GetIBCMetadataProposal looks up a previously accepted IBCMetadataProposal for a given token
+// contract, if governance has ever set denom metadata for it. Backs the IBCMetadataProposal gRPC
+// query that CmdQueryIBCMetadataProposal calls.
+func (k Keeper) GetIBCMetadataProposal(ctx sdk.Context, chainID types.ChainID, tokenContract types.EthAddress) (banktypes.Metadata, bool) {
+	denom := types.GravityDenom(chainID, tokenContract)
+	return k.bankKeeper.GetDenomMetaData(ctx, denom)
+}
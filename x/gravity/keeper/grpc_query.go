@@ -0,0 +1,48 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mineplexio/mineplex-2-node/x/gravity/types"
+)
+
+// this file implements types.QueryServer, the gRPC surface the module's query CLI commands talk
+// to. It just adapts the keeper methods that already did the real work into the request/response
+// types defined in query.proto
+
+var _ types.QueryServer = Keeper{}
+
+// IBCMetadataProposal implements the IBCMetadataProposal RPC, returning the bank denom metadata
+// governance has set for a bridged ERC20 via an IBCMetadataProposal, if any
+func (k Keeper) IBCMetadataProposal(c context.Context, req *types.QueryIBCMetadataProposalRequest) (*types.QueryIBCMetadataProposalResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	tokenContract, err := types.NewEthAddress(req.TokenContract)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid token contract address")
+	}
+
+	ctx := sdk.UnwrapSDKContext(c)
+	metadata, found := k.GetIBCMetadataProposal(ctx, types.ChainID(req.ChainId), *tokenContract)
+	if !found {
+		return nil, status.Error(codes.NotFound, "no metadata proposal on record for this token contract")
+	}
+
+	return &types.QueryIBCMetadataProposalResponse{Metadata: metadata}, nil
+}
+
+// PendingAirdrops implements the PendingAirdrops RPC, listing every airdrop proposal still
+// draining out of the PendingAirdropQueue
+func (k Keeper) PendingAirdrops(c context.Context, req *types.QueryPendingAirdropsRequest) (*types.QueryPendingAirdropsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	ctx := sdk.UnwrapSDKContext(c)
+	return &types.QueryPendingAirdropsResponse{Queues: k.QueryPendingAirdrops(ctx)}, nil
+}
@@ -0,0 +1,201 @@
+package keeper
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	disttypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mineplexio/mineplex-2-node/x/gravity/types"
+)
+
+// this file contains the keeper-side mechanics of a MerkleAirdropProposal: the claim campaign
+// created by HandleMerkleAirdropProposal, the bitset that guards against double-claims, and the
+// EndBlocker sweep that returns unclaimed funds to the community pool once a campaign expires
+
+// airdropCampaignEscrowAddress derives the address that holds one campaign's funds in escrow
+// between funding and claim/refund. This is deliberately a plain derived account, not a module
+// account registered with AccountKeeper: module names are resolved through the static maccPerms
+// map wired up once at app init, so a fresh name built from a runtime-assigned campaign id could
+// never be pre-registered there. It is only ever the account side of a transfer: claims pay out
+// of it with ordinary SendCoins, and funding and sweeping use SendCoinsFromModuleToAccount and
+// SendCoinsFromAccountToModule with the distribution module on the module side
+func airdropCampaignEscrowAddress(campaignID uint64) sdk.AccAddress {
+	return authtypes.NewModuleAddress(fmt.Sprintf("gravity-airdrop-campaign-%d", campaignID))
+}
+
+// SetAirdropCampaign stores or updates a claim campaign
+func (k Keeper) SetAirdropCampaign(ctx sdk.Context, campaign types.AirdropCampaign) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetAirdropCampaignKey(campaign.CampaignId), k.cdc.MustMarshal(&campaign))
+}
+
+// GetAirdropCampaign looks up a claim campaign by id
+func (k Keeper) GetAirdropCampaign(ctx sdk.Context, campaignID uint64) (types.AirdropCampaign, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetAirdropCampaignKey(campaignID))
+	if bz == nil {
+		return types.AirdropCampaign{}, false
+	}
+	var campaign types.AirdropCampaign
+	k.cdc.MustUnmarshal(bz, &campaign)
+	return campaign, true
+}
+
+// DeleteAirdropCampaign removes a claim campaign once it has been fully swept
+func (k Keeper) DeleteAirdropCampaign(ctx sdk.Context, campaignID uint64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetAirdropCampaignKey(campaignID))
+}
+
+// IterateAirdropCampaigns iterates over every in-flight claim campaign, used by the EndBlocker
+// sweep and by the genesis exporter
+func (k Keeper) IterateAirdropCampaigns(ctx sdk.Context, cb func(campaign types.AirdropCampaign) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.AirdropCampaignKey)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var campaign types.AirdropCampaign
+		k.cdc.MustUnmarshal(iter.Value(), &campaign)
+		if cb(campaign) {
+			break
+		}
+	}
+}
+
+// GetNextAirdropCampaignID returns a fresh, never reused campaign id and advances the counter
+func (k Keeper) GetNextAirdropCampaignID(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	var id uint64
+	if bz := store.Get(types.AirdropCampaignNonceKey); bz != nil {
+		id = sdk.BigEndianToUint64(bz)
+	}
+	id++
+	store.Set(types.AirdropCampaignNonceKey, sdk.Uint64ToBigEndian(id))
+	return id
+}
+
+// IsAirdropClaimed checks the (campaignID, index) bit in the ClaimedBitmap
+func (k Keeper) IsAirdropClaimed(ctx sdk.Context, campaignID uint64, index uint64) bool {
+	store := ctx.KVStore(k.storeKey)
+	key := types.GetAirdropClaimedBitmapKey(campaignID, index/8)
+	word := store.Get(key)
+	if word == nil {
+		return false
+	}
+	return word[0]&(1<<(index%8)) != 0
+}
+
+// SetAirdropClaimed flips the (campaignID, index) bit in the ClaimedBitmap
+func (k Keeper) SetAirdropClaimed(ctx sdk.Context, campaignID uint64, index uint64) {
+	store := ctx.KVStore(k.storeKey)
+	key := types.GetAirdropClaimedBitmapKey(campaignID, index/8)
+	word := store.Get(key)
+	if word == nil {
+		word = []byte{0}
+	}
+	word[0] |= 1 << (index % 8)
+	store.Set(key, word)
+}
+
+// ExportAirdropCampaigns collects every in-flight claim campaign for inclusion in the exported
+// genesis state, so that a chain upgrade or restart doesn't strand funds mid-campaign
+func (k Keeper) ExportAirdropCampaigns(ctx sdk.Context) []types.AirdropCampaign {
+	var campaigns []types.AirdropCampaign
+	k.IterateAirdropCampaigns(ctx, func(campaign types.AirdropCampaign) bool {
+		campaigns = append(campaigns, campaign)
+		return false
+	})
+	return campaigns
+}
+
+// ClaimAirdrop verifies a Merkle proof against the campaign's stored root and pays out the leaf
+// amount to the claimer, rejecting double-claims via the ClaimedBitmap. Claims are only accepted
+// before ExpiryHeight and, if the campaign set a ClaimWindow, before CreatedHeight+ClaimWindow
+func (k Keeper) ClaimAirdrop(ctx sdk.Context, msg *types.MsgClaimAirdrop) error {
+	campaign, found := k.GetAirdropCampaign(ctx, msg.CampaignId)
+	if !found {
+		return sdkerrors.Wrap(types.ErrUnknown, "no such airdrop campaign")
+	}
+	if uint64(ctx.BlockHeight()) >= campaign.ExpiryHeight {
+		return sdkerrors.Wrap(types.ErrInvalid, "airdrop campaign has expired")
+	}
+	if campaign.ClaimWindow > 0 && uint64(ctx.BlockHeight()) >= campaign.CreatedHeight+uint64(campaign.ClaimWindow) {
+		return sdkerrors.Wrap(types.ErrInvalid, "airdrop campaign's claim window has closed")
+	}
+	if k.IsAirdropClaimed(ctx, campaign.CampaignId, msg.Index) {
+		return sdkerrors.Wrap(types.ErrInvalid, "airdrop already claimed")
+	}
+
+	claimer, err := sdk.AccAddressFromBech32(msg.Claimer)
+	if err != nil {
+		return sdkerrors.Wrap(types.ErrInvalid, "invalid claimer address")
+	}
+
+	leaf := crypto.Keccak256(
+		sdk.Uint64ToBigEndian(msg.Index),
+		claimer.Bytes(),
+		sdk.Uint64ToBigEndian(msg.Amount),
+	)
+	if !verifyMerkleProof(leaf, msg.Proof, campaign.MerkleRoot) {
+		return sdkerrors.Wrap(types.ErrInvalid, "invalid merkle proof")
+	}
+
+	k.SetAirdropClaimed(ctx, campaign.CampaignId, msg.Index)
+	campaign.ClaimedAmount += msg.Amount
+	k.SetAirdropCampaign(ctx, campaign)
+
+	escrowAddr := airdropCampaignEscrowAddress(campaign.CampaignId)
+	amount := sdk.NewCoins(sdk.NewCoin(campaign.Denom, sdk.NewIntFromUint64(msg.Amount)))
+	return k.bankKeeper.SendCoins(ctx, escrowAddr, claimer, amount)
+}
+
+// verifyMerkleProof walks an OpenZeppelin-style, pair-wise sorted Merkle proof up to root, hashing
+// with keccak256 at each step to match the leaf hash ClaimAirdrop builds with crypto.Keccak256 -
+// mixing hash functions between leaf and internal nodes would mean no off-chain tree-builder's
+// proof could ever verify
+func verifyMerkleProof(leaf []byte, proof [][]byte, root []byte) bool {
+	computed := leaf
+	for _, sibling := range proof {
+		if bytes.Compare(computed, sibling) <= 0 {
+			computed = crypto.Keccak256(computed, sibling)
+		} else {
+			computed = crypto.Keccak256(sibling, computed)
+		}
+	}
+	return bytes.Equal(computed, root)
+}
+
+// sweepExpiredAirdropCampaigns returns the unclaimed remainder of any campaign whose
+// ExpiryHeight has passed back to the community pool, preserving the supply invariant that
+// HandleMerkleAirdropProposal relied on when it first funded the campaign
+func (k Keeper) sweepExpiredAirdropCampaigns(ctx sdk.Context) {
+	var expired []types.AirdropCampaign
+	k.IterateAirdropCampaigns(ctx, func(campaign types.AirdropCampaign) bool {
+		if uint64(ctx.BlockHeight()) >= campaign.ExpiryHeight {
+			expired = append(expired, campaign)
+		}
+		return false
+	})
+
+	for _, campaign := range expired {
+		remainder := campaign.TotalAmount - campaign.ClaimedAmount
+		if remainder > 0 {
+			escrowAddr := airdropCampaignEscrowAddress(campaign.CampaignId)
+			remainderCoins := sdk.NewCoins(sdk.NewCoin(campaign.Denom, sdk.NewIntFromUint64(remainder)))
+			if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, escrowAddr, disttypes.ModuleName, remainderCoins); err != nil {
+				ctx.Logger().Error("failed to sweep expired airdrop campaign", "campaign", campaign.CampaignId, "error", err)
+				continue
+			}
+			feePool := k.DistKeeper.GetFeePool(ctx)
+			feePool.CommunityPool = feePool.CommunityPool.Add(sdk.NewDecCoinsFromCoins(remainderCoins...)...)
+			k.DistKeeper.SetFeePool(ctx, feePool)
+		}
+		ctx.Logger().Info("Swept expired merkle airdrop campaign", "campaign", campaign.CampaignId, "remainder", remainder)
+		k.DeleteAirdropCampaign(ctx, campaign.CampaignId)
+	}
+}